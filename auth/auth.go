@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jamie/gqlgen-crud/models"
+)
+
+// ErrUnauthenticated is returned by resolvers that require a caller when
+// the request carried no valid bearer token.
+var ErrUnauthenticated = errors.New("authentication required")
+
+type ctxKey string
+
+const userCtxKey ctxKey = "auth_user"
+
+// claims are the fields this service expects in a bearer JWT.
+type claims struct {
+	jwt.RegisteredClaims
+	UserID int         `json:"userId"`
+	Name   string      `json:"name"`
+	Role   models.Role `json:"role"`
+}
+
+// Middleware validates the bearer JWT on incoming requests, if any, and
+// stashes the resulting *models.User on the request context. Requests with
+// no token, or a token that fails to parse, are passed through
+// unauthenticated so that public fields (e.g. createUser) keep working;
+// resolvers that require a caller enforce that themselves via ForContext or
+// the @hasRole directive.
+func Middleware(secret []byte, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenString := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if tokenString == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		user, err := ParseToken(secret, tokenString)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), user)))
+	})
+}
+
+// ParseToken validates tokenString as a bearer JWT signed with secret using
+// HS256 and returns the user it encodes. Transports that can't run
+// Middleware (e.g. a WebSocket's connection_init payload, which arrives
+// after the HTTP handshake) call this directly.
+func ParseToken(secret []byte, tokenString string) (*models.User, error) {
+	c := &claims{}
+	_, err := jwt.ParseWithClaims(tokenString, c, func(t *jwt.Token) (interface{}, error) {
+		return secret, nil
+	}, jwt.WithValidMethods([]string{"HS256"}))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.User{ID: c.UserID, Name: c.Name, Role: c.Role}, nil
+}
+
+// WithUser returns a copy of ctx carrying user, the way Middleware does for
+// HTTP requests.
+func WithUser(ctx context.Context, user *models.User) context.Context {
+	return context.WithValue(ctx, userCtxKey, user)
+}
+
+// ForContext returns the authenticated user stashed on ctx by Middleware or
+// WithUser, or nil if the request was unauthenticated.
+func ForContext(ctx context.Context) *models.User {
+	user, _ := ctx.Value(userCtxKey).(*models.User)
+	return user
+}
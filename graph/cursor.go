@@ -0,0 +1,37 @@
+package graph
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jamie/gqlgen-crud/graph/model"
+)
+
+// encodeCursor builds an opaque cursor from the field a connection is
+// ordered by and the id of the row it points at.
+func encodeCursor(field model.TodoOrderField, id int) string {
+	raw := fmt.Sprintf("%s:%d", field, id)
+	return base64.StdEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor is the inverse of encodeCursor.
+func decodeCursor(cursor string) (model.TodoOrderField, int, error) {
+	raw, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	field, idPart, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return "", 0, fmt.Errorf("invalid cursor: %q", raw)
+	}
+
+	id, err := strconv.Atoi(idPart)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	return model.TodoOrderField(field), id, nil
+}
@@ -0,0 +1,31 @@
+package directives
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/jamie/gqlgen-crud/auth"
+	"github.com/jamie/gqlgen-crud/models"
+)
+
+// ErrInsufficientRole is returned when the caller is authenticated but
+// doesn't hold the role a field requires. It is distinct from
+// graph.ErrForbidden (denied for acting on another user's todos or todo
+// events) so clients can branch
+// on which check failed.
+var ErrInsufficientRole = errors.New("access denied: insufficient role")
+
+// HasRole implements the @hasRole schema directive: it requires an
+// authenticated caller holding at least the given role before resolving
+// the annotated field.
+func HasRole(ctx context.Context, obj interface{}, next graphql.Resolver, role models.Role) (interface{}, error) {
+	user := auth.ForContext(ctx)
+	if user == nil {
+		return nil, auth.ErrUnauthenticated
+	}
+	if !user.Role.IsValid() || (role == models.RoleAdmin && user.Role != models.RoleAdmin) {
+		return nil, ErrInsufficientRole
+	}
+	return next(ctx)
+}
@@ -0,0 +1,12 @@
+package graph
+
+import "errors"
+
+// ErrTodoNotFound is returned when a mutation or query references a todo
+// that doesn't exist.
+var ErrTodoNotFound = errors.New("todo not found")
+
+// ErrForbidden is returned when the caller tries to create or mutate a todo
+// owned by another user, or to subscribe to another user's (or every user's)
+// todo events, without holding an admin role.
+var ErrForbidden = errors.New("access denied: not the owner of this todo")
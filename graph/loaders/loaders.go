@@ -0,0 +1,35 @@
+package loaders
+
+import (
+	"context"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+type ctxKey string
+
+const loadersKey ctxKey = "dataloaders"
+
+// Loaders groups every per-request dataloader. It is stashed on the request
+// context by Middleware and retrieved in field resolvers via For.
+type Loaders struct {
+	UserByID *UserLoader
+}
+
+// Middleware builds a fresh set of loaders for every incoming request and
+// attaches them to the request context.
+func Middleware(db *gorm.DB, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		loaders := &Loaders{
+			UserByID: NewUserLoader(db),
+		}
+		ctx := context.WithValue(r.Context(), loadersKey, loaders)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// For returns the Loaders stashed on ctx by Middleware.
+func For(ctx context.Context) *Loaders {
+	return ctx.Value(loadersKey).(*Loaders)
+}
@@ -0,0 +1,119 @@
+package loaders
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jamie/gqlgen-crud/models"
+	"gorm.io/gorm"
+)
+
+const (
+	userLoaderWait     = 2 * time.Millisecond
+	userLoaderMaxBatch = 100
+)
+
+// ErrUserNotFound is returned when a requested user id doesn't exist, e.g.
+// a Todo.UserID left dangling by a createTodo call that named a user that
+// was never created.
+var ErrUserNotFound = errors.New("user not found")
+
+// UserLoader batches and caches User lookups by ID within a single request,
+// so resolving Todo.user for a page of todos costs one query instead of one
+// per todo.
+type UserLoader struct {
+	db *gorm.DB
+
+	mu    sync.Mutex
+	batch []userBatchItem
+	timer *time.Timer
+	cache map[int]*userResult
+}
+
+type userBatchItem struct {
+	id     int
+	result chan userResult
+}
+
+type userResult struct {
+	user *models.User
+	err  error
+}
+
+func NewUserLoader(db *gorm.DB) *UserLoader {
+	return &UserLoader{
+		db:    db,
+		cache: make(map[int]*userResult),
+	}
+}
+
+// Load returns the User with the given ID, batching this call together with
+// any others made within the wait window.
+func (l *UserLoader) Load(ctx context.Context, userID int) (*models.User, error) {
+	l.mu.Lock()
+	if cached, ok := l.cache[userID]; ok {
+		l.mu.Unlock()
+		return cached.user, cached.err
+	}
+
+	item := userBatchItem{id: userID, result: make(chan userResult, 1)}
+	l.batch = append(l.batch, item)
+
+	if l.timer == nil {
+		l.timer = time.AfterFunc(userLoaderWait, l.dispatch)
+	}
+	if len(l.batch) >= userLoaderMaxBatch {
+		l.timer.Stop()
+		go l.dispatch()
+	}
+	l.mu.Unlock()
+
+	select {
+	case res := <-item.result:
+		return res.user, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *UserLoader) dispatch() {
+	l.mu.Lock()
+	batch := l.batch
+	l.batch = nil
+	l.timer = nil
+	l.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	ids := make([]int, 0, len(batch))
+	seen := make(map[int]bool, len(batch))
+	for _, item := range batch {
+		if !seen[item.id] {
+			seen[item.id] = true
+			ids = append(ids, item.id)
+		}
+	}
+
+	var users []*models.User
+	err := l.db.Where("id IN ?", ids).Find(&users).Error
+
+	byID := make(map[int]*models.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+
+	l.mu.Lock()
+	for _, item := range batch {
+		res := userResult{user: byID[item.id], err: err}
+		if res.err == nil && res.user == nil {
+			res.err = ErrUserNotFound
+		}
+		l.cache[item.id] = &res
+		item.result <- res
+	}
+	l.mu.Unlock()
+}
@@ -2,6 +2,14 @@
 
 package model
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jamie/gqlgen-crud/models"
+)
+
 type EditTodo struct {
 	ID   int    `json:"id"`
 	Text string `json:"text"`
@@ -19,3 +27,162 @@ type NewTodo struct {
 type NewUser struct {
 	Name string `json:"name"`
 }
+
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor"`
+}
+
+type TodoConnection struct {
+	Edges      []*TodoEdge `json:"edges"`
+	PageInfo   *PageInfo   `json:"pageInfo"`
+	TotalCount int         `json:"totalCount"`
+}
+
+type TodoEdge struct {
+	Node   *models.Todo `json:"node"`
+	Cursor string       `json:"cursor"`
+}
+
+type TodoEvent struct {
+	Type TodoEventType `json:"type"`
+	Todo *models.Todo  `json:"todo"`
+}
+
+type TodoFilter struct {
+	Done         *bool   `json:"done"`
+	UserID       *int    `json:"userId"`
+	TextContains *string `json:"textContains"`
+}
+
+type TodoOrder struct {
+	Field     TodoOrderField `json:"field"`
+	Direction OrderDirection `json:"direction"`
+}
+
+type OrderDirection string
+
+const (
+	OrderDirectionAsc  OrderDirection = "ASC"
+	OrderDirectionDesc OrderDirection = "DESC"
+)
+
+var AllOrderDirection = []OrderDirection{
+	OrderDirectionAsc,
+	OrderDirectionDesc,
+}
+
+func (e OrderDirection) IsValid() bool {
+	switch e {
+	case OrderDirectionAsc, OrderDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e OrderDirection) String() string {
+	return string(e)
+}
+
+func (e *OrderDirection) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrderDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrderDirection", str)
+	}
+	return nil
+}
+
+func (e OrderDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TodoOrderField string
+
+const (
+	TodoOrderFieldID   TodoOrderField = "ID"
+	TodoOrderFieldText TodoOrderField = "TEXT"
+	TodoOrderFieldDone TodoOrderField = "DONE"
+)
+
+var AllTodoOrderField = []TodoOrderField{
+	TodoOrderFieldID,
+	TodoOrderFieldText,
+	TodoOrderFieldDone,
+}
+
+func (e TodoOrderField) IsValid() bool {
+	switch e {
+	case TodoOrderFieldID, TodoOrderFieldText, TodoOrderFieldDone:
+		return true
+	}
+	return false
+}
+
+func (e TodoOrderField) String() string {
+	return string(e)
+}
+
+func (e *TodoOrderField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TodoOrderField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TodoOrderField", str)
+	}
+	return nil
+}
+
+func (e TodoOrderField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TodoEventType string
+
+const (
+	TodoEventTypeCreated TodoEventType = "CREATED"
+	TodoEventTypeUpdated TodoEventType = "UPDATED"
+	TodoEventTypeDeleted TodoEventType = "DELETED"
+)
+
+var AllTodoEventType = []TodoEventType{
+	TodoEventTypeCreated,
+	TodoEventTypeUpdated,
+	TodoEventTypeDeleted,
+}
+
+func (e TodoEventType) IsValid() bool {
+	switch e {
+	case TodoEventTypeCreated, TodoEventTypeUpdated, TodoEventTypeDeleted:
+		return true
+	}
+	return false
+}
+
+func (e TodoEventType) String() string {
+	return string(e)
+}
+
+func (e *TodoEventType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TodoEventType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TodoEventType", str)
+	}
+	return nil
+}
+
+func (e TodoEventType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
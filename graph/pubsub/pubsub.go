@@ -0,0 +1,94 @@
+package pubsub
+
+import (
+	"sync"
+
+	"github.com/jamie/gqlgen-crud/models"
+)
+
+// EventType identifies what happened to a Todo.
+type EventType string
+
+const (
+	EventCreated EventType = "CREATED"
+	EventUpdated EventType = "UPDATED"
+	EventDeleted EventType = "DELETED"
+)
+
+// Event is published whenever a todo mutation succeeds.
+type Event struct {
+	Type EventType
+	Todo *models.Todo
+}
+
+const subscriberBuffer = 8
+
+// Broker fans todo events out to subscribers. Subscribing with a userID
+// receives only that user's events; subscribing with nil receives every
+// event.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[int]map[chan Event]struct{}
+	global      map[chan Event]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[int]map[chan Event]struct{}),
+		global:      make(map[chan Event]struct{}),
+	}
+}
+
+// Subscribe returns a channel of events for userID and an unsubscribe func
+// that must be called once the subscriber is done, to release the channel.
+func (b *Broker) Subscribe(userID *int) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	if userID == nil {
+		b.global[ch] = struct{}{}
+	} else {
+		if b.subscribers[*userID] == nil {
+			b.subscribers[*userID] = make(map[chan Event]struct{})
+		}
+		b.subscribers[*userID][ch] = struct{}{}
+	}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if userID == nil {
+			delete(b.global, ch)
+		} else if subs, ok := b.subscribers[*userID]; ok {
+			delete(subs, ch)
+			if len(subs) == 0 {
+				delete(b.subscribers, *userID)
+			}
+		}
+		close(ch)
+	}
+
+	return ch, unsubscribe
+}
+
+// Publish delivers event to every subscriber of event.Todo.UserID plus every
+// global subscriber. A subscriber that isn't keeping up has the event
+// dropped rather than blocking the publisher.
+func (b *Broker) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[event.Todo.UserID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+	for ch := range b.global {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
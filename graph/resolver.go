@@ -0,0 +1,20 @@
+package graph
+
+import (
+	"github.com/jamie/gqlgen-crud/graph/pubsub"
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/99designs/gqlgen generate
+
+// This file will not be regenerated automatically.
+//
+// It serves as dependency injection for your app, add any dependencies you
+// require here.
+
+// Resolver is the root GraphQL resolver. It holds everything the individual
+// resolvers need to serve a request.
+type Resolver struct {
+	DB     *gorm.DB
+	Broker *pubsub.Broker
+}
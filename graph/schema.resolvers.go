@@ -5,39 +5,89 @@ package graph
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"github.com/jamie/gqlgen-crud/auth"
 	"github.com/jamie/gqlgen-crud/graph/generated"
+	"github.com/jamie/gqlgen-crud/graph/loaders"
 	"github.com/jamie/gqlgen-crud/graph/model"
+	"github.com/jamie/gqlgen-crud/graph/pubsub"
 	"github.com/jamie/gqlgen-crud/models"
-	mysql "github.com/jamie/gqlgen-crud/mysql"
+	"gorm.io/gorm"
 )
 
-var db = mysql.Connection()
+func (r *todoResolver) User(ctx context.Context, obj *models.Todo) (*models.User, error) {
+	return loaders.For(ctx).UserByID.Load(ctx, obj.UserID)
+}
 
 func (r *mutationResolver) CreateTodo(ctx context.Context, input model.NewTodo) (*models.Todo, error) {
+	caller := auth.ForContext(ctx)
+	if caller == nil {
+		return nil, auth.ErrUnauthenticated
+	}
+	if input.UserID != caller.ID && caller.Role != models.RoleAdmin {
+		return nil, ErrForbidden
+	}
+
 	todo := models.Todo{
 		Text:   input.Text,
 		UserID: input.UserID,
 		Done:   false,
 	}
-	db.Create(&todo)
+	if err := r.DB.WithContext(ctx).Create(&todo).Error; err != nil {
+		return nil, err
+	}
+	r.Broker.Publish(pubsub.Event{Type: pubsub.EventCreated, Todo: &todo})
 	return &todo, nil
 }
 
 func (r *mutationResolver) UpdateTodo(ctx context.Context, input model.EditTodo) (*models.Todo, error) {
-	todo := models.Todo{ID: input.ID}
-	db.First(&todo)
+	var todo models.Todo
+	if err := r.DB.WithContext(ctx).First(&todo, input.ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTodoNotFound
+		}
+		return nil, err
+	}
+
+	caller := auth.ForContext(ctx)
+	if caller == nil {
+		return nil, auth.ErrUnauthenticated
+	}
+	if todo.UserID != caller.ID && caller.Role != models.RoleAdmin {
+		return nil, ErrForbidden
+	}
+
 	todo.Text = input.Text
-	db.Model(&models.Todo{}).Update(&todo)
+	if err := r.DB.WithContext(ctx).Save(&todo).Error; err != nil {
+		return nil, err
+	}
+	r.Broker.Publish(pubsub.Event{Type: pubsub.EventUpdated, Todo: &todo})
 	return &todo, nil
 }
 
 func (r *mutationResolver) DeleteTodo(ctx context.Context, input int) (*models.Todo, error) {
-	todo := models.Todo{
-		ID: input,
+	var todo models.Todo
+	if err := r.DB.WithContext(ctx).First(&todo, input).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTodoNotFound
+		}
+		return nil, err
+	}
+
+	caller := auth.ForContext(ctx)
+	if caller == nil {
+		return nil, auth.ErrUnauthenticated
 	}
-	db.First(&todo)
-	db.Delete(&todo)
+	if todo.UserID != caller.ID && caller.Role != models.RoleAdmin {
+		return nil, ErrForbidden
+	}
+
+	if err := r.DB.WithContext(ctx).Delete(&todo).Error; err != nil {
+		return nil, err
+	}
+	r.Broker.Publish(pubsub.Event{Type: pubsub.EventDeleted, Todo: &todo})
 	return &todo, nil
 }
 
@@ -45,33 +95,219 @@ func (r *mutationResolver) CreateUser(ctx context.Context, input model.NewUser)
 	user := models.User{
 		Name: input.Name,
 	}
-	db.Create(&user)
+	if err := r.DB.WithContext(ctx).Create(&user).Error; err != nil {
+		return nil, err
+	}
 	return &user, nil
 }
 
-func (r *queryResolver) Todos(ctx context.Context) ([]*models.Todo, error) {
+const defaultTodosPageSize = 20
+
+func (r *queryResolver) Todos(ctx context.Context, first *int, after *string, filter *model.TodoFilter, orderBy *model.TodoOrder) (*model.TodoConnection, error) {
+	limit := defaultTodosPageSize
+	if first != nil && *first > 0 {
+		limit = *first
+	}
+
+	orderField := model.TodoOrderFieldID
+	direction := model.OrderDirectionAsc
+	if orderBy != nil {
+		orderField = orderBy.Field
+		direction = orderBy.Direction
+	}
+
+	column, err := todoOrderColumn(orderField)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.DB.WithContext(ctx).Model(&models.Todo{})
+
+	if filter != nil {
+		if filter.Done != nil {
+			query = query.Where("done = ?", *filter.Done)
+		}
+		if filter.UserID != nil {
+			query = query.Where("user_id = ?", *filter.UserID)
+		}
+		if filter.TextContains != nil {
+			query = query.Where("text LIKE ?", "%"+*filter.TextContains+"%")
+		}
+	}
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, err
+	}
+
+	orderClause := column + " ASC, id ASC"
+	cursorOp := ">"
+	if direction == model.OrderDirectionDesc {
+		orderClause = column + " DESC, id DESC"
+		cursorOp = "<"
+	}
+
+	if after != nil {
+		afterField, afterID, err := decodeCursor(*after)
+		if err != nil {
+			return nil, err
+		}
+		if afterField != orderField {
+			return nil, fmt.Errorf("cursor was issued for order field %s, not %s", afterField, orderField)
+		}
+
+		var anchor models.Todo
+		if err := r.DB.WithContext(ctx).Select(column, "id").First(&anchor, afterID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("invalid cursor: row no longer exists")
+			}
+			return nil, err
+		}
+		afterValue, err := todoOrderValue(&anchor, orderField)
+		if err != nil {
+			return nil, err
+		}
+
+		// Compare on (orderColumn, id) as a tuple so rows that tie on the
+		// order column still page correctly, keyed off id as a tiebreaker.
+		query = query.Where(
+			fmt.Sprintf("%s %s ? OR (%s = ? AND id %s ?)", column, cursorOp, column, cursorOp),
+			afterValue, afterValue, afterID,
+		)
+	}
+
 	var todos []*models.Todo
-	db.Preload("User").Find(&todos)
-	return todos, nil
+	if err := query.Order(orderClause).Limit(limit + 1).Find(&todos).Error; err != nil {
+		return nil, err
+	}
+
+	hasNextPage := len(todos) > limit
+	if hasNextPage {
+		todos = todos[:limit]
+	}
+
+	edges := make([]*model.TodoEdge, 0, len(todos))
+	for _, todo := range todos {
+		edges = append(edges, &model.TodoEdge{
+			Node:   todo,
+			Cursor: encodeCursor(orderField, todo.ID),
+		})
+	}
+
+	pageInfo := &model.PageInfo{HasNextPage: hasNextPage}
+	if len(edges) > 0 {
+		pageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &model.TodoConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo,
+		TotalCount: int(totalCount),
+	}, nil
+}
+
+func todoOrderColumn(field model.TodoOrderField) (string, error) {
+	switch field {
+	case model.TodoOrderFieldID:
+		return "id", nil
+	case model.TodoOrderFieldText:
+		return "text", nil
+	case model.TodoOrderFieldDone:
+		return "done", nil
+	default:
+		return "", fmt.Errorf("unsupported order field: %s", field)
+	}
+}
+
+// todoOrderValue reads the value todo was ordered by, so a cursor pointing
+// at it can be compared against the rest of the page as a (column, id)
+// tuple instead of by id alone.
+func todoOrderValue(todo *models.Todo, field model.TodoOrderField) (interface{}, error) {
+	switch field {
+	case model.TodoOrderFieldID:
+		return todo.ID, nil
+	case model.TodoOrderFieldText:
+		return todo.Text, nil
+	case model.TodoOrderFieldDone:
+		return todo.Done, nil
+	default:
+		return nil, fmt.Errorf("unsupported order field: %s", field)
+	}
 }
 
 func (r *queryResolver) Users(ctx context.Context) ([]*models.User, error) {
 	var users []*models.User
-	db.Find(&users)
+	if err := r.DB.WithContext(ctx).Find(&users).Error; err != nil {
+		return nil, err
+	}
 	return users, nil
 }
 
 func (r *queryResolver) Todo(ctx context.Context, input *model.FetchTodo) (*models.Todo, error) {
 	var todo models.Todo
-	db.Preload("User").First(&todo, input.ID)
+	if err := r.DB.WithContext(ctx).First(&todo, input.ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTodoNotFound
+		}
+		return nil, err
+	}
 	return &todo, nil
 }
 
+func (r *subscriptionResolver) TodoChanged(ctx context.Context, userID *int) (<-chan *model.TodoEvent, error) {
+	caller := auth.ForContext(ctx)
+	if caller == nil {
+		return nil, auth.ErrUnauthenticated
+	}
+	// A nil userID subscribes to every user's events, so only an admin may
+	// pass it; everyone else may only subscribe to their own.
+	if userID == nil {
+		if caller.Role != models.RoleAdmin {
+			return nil, ErrForbidden
+		}
+	} else if *userID != caller.ID && caller.Role != models.RoleAdmin {
+		return nil, ErrForbidden
+	}
+
+	events, unsubscribe := r.Broker.Subscribe(userID)
+	out := make(chan *model.TodoEvent)
+
+	go func() {
+		defer unsubscribe()
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				select {
+				case out <- &model.TodoEvent{Type: model.TodoEventType(event.Type), Todo: event.Todo}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// Subscription returns generated.SubscriptionResolver implementation.
+func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
+
+// Todo returns generated.TodoResolver implementation.
+func (r *Resolver) Todo() generated.TodoResolver { return &todoResolver{r} }
+
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
+type todoResolver struct{ *Resolver }
@@ -0,0 +1,168 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jamie/gqlgen-crud/graph/model"
+	"github.com/jamie/gqlgen-crud/graph/pubsub"
+	"github.com/jamie/gqlgen-crud/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestResolver(t *testing.T) *Resolver {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Todo{}, &models.User{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+
+	return &Resolver{DB: db, Broker: pubsub.NewBroker()}
+}
+
+func seedTodos(t *testing.T, db *gorm.DB, todos []models.Todo) {
+	t.Helper()
+	for i := range todos {
+		if err := db.Create(&todos[i]).Error; err != nil {
+			t.Fatalf("seed todo: %v", err)
+		}
+	}
+}
+
+// collectAllPages walks every page of the todos connection, following
+// endCursor until hasNextPage is false, and returns the todo ids in the
+// order the connection served them.
+func collectAllPages(t *testing.T, r *queryResolver, pageSize int, filter *model.TodoFilter, orderBy *model.TodoOrder) []int {
+	t.Helper()
+
+	var ids []int
+	var after *string
+	for {
+		conn, err := r.Todos(context.Background(), &pageSize, after, filter, orderBy)
+		if err != nil {
+			t.Fatalf("Todos: %v", err)
+		}
+		for _, edge := range conn.Edges {
+			ids = append(ids, edge.Node.ID)
+		}
+		if !conn.PageInfo.HasNextPage {
+			break
+		}
+		after = conn.PageInfo.EndCursor
+	}
+	return ids
+}
+
+func TestTodosForwardPagination(t *testing.T) {
+	resolver := newTestResolver(t)
+	seedTodos(t, resolver.DB, []models.Todo{
+		{Text: "alpha", Done: false, UserID: 1},
+		{Text: "bravo", Done: false, UserID: 1},
+		{Text: "charlie", Done: true, UserID: 1},
+		{Text: "delta", Done: false, UserID: 2},
+		{Text: "echo", Done: true, UserID: 2},
+	})
+
+	r := &queryResolver{resolver}
+	ids := collectAllPages(t, r, 2, nil, nil)
+
+	if len(ids) != 5 {
+		t.Fatalf("expected 5 todos across pages, got %d: %v", len(ids), ids)
+	}
+	for i, id := range ids {
+		if id != i+1 {
+			t.Errorf("page walk returned ids out of order: %v", ids)
+			break
+		}
+	}
+}
+
+func TestTodosPaginationStableUnderNonIDOrder(t *testing.T) {
+	resolver := newTestResolver(t)
+	// Text order and id order deliberately disagree.
+	seedTodos(t, resolver.DB, []models.Todo{
+		{Text: "delta", Done: false, UserID: 1},
+		{Text: "bravo", Done: false, UserID: 1},
+		{Text: "echo", Done: false, UserID: 1},
+		{Text: "alpha", Done: false, UserID: 1},
+		{Text: "charlie", Done: false, UserID: 1},
+	})
+
+	r := &queryResolver{resolver}
+	orderBy := &model.TodoOrder{Field: model.TodoOrderFieldText, Direction: model.OrderDirectionAsc}
+	ids := collectAllPages(t, r, 2, nil, orderBy)
+
+	var texts []string
+	for _, id := range ids {
+		var todo models.Todo
+		if err := resolver.DB.First(&todo, id).Error; err != nil {
+			t.Fatalf("reload todo %d: %v", id, err)
+		}
+		texts = append(texts, todo.Text)
+	}
+
+	want := []string{"alpha", "bravo", "charlie", "delta", "echo"}
+	if len(texts) != len(want) {
+		t.Fatalf("expected %v, got %v", want, texts)
+	}
+	for i := range want {
+		if texts[i] != want[i] {
+			t.Fatalf("page walk ordered by text out of order: got %v, want %v", texts, want)
+		}
+	}
+}
+
+func TestTodosCursorRejectsMismatchedOrderField(t *testing.T) {
+	resolver := newTestResolver(t)
+	seedTodos(t, resolver.DB, []models.Todo{
+		{Text: "alpha", Done: false, UserID: 1},
+		{Text: "bravo", Done: false, UserID: 1},
+	})
+
+	r := &queryResolver{resolver}
+	first := 1
+	conn, err := r.Todos(context.Background(), &first, nil, nil, &model.TodoOrder{Field: model.TodoOrderFieldID, Direction: model.OrderDirectionAsc})
+	if err != nil {
+		t.Fatalf("Todos: %v", err)
+	}
+
+	textOrder := &model.TodoOrder{Field: model.TodoOrderFieldText, Direction: model.OrderDirectionAsc}
+	if _, err := r.Todos(context.Background(), &first, conn.PageInfo.EndCursor, nil, textOrder); err == nil {
+		t.Fatal("expected an error reusing an id cursor with a text orderBy, got nil")
+	}
+}
+
+func TestTodosCombinedFilters(t *testing.T) {
+	resolver := newTestResolver(t)
+	seedTodos(t, resolver.DB, []models.Todo{
+		{Text: "buy milk", Done: false, UserID: 1},
+		{Text: "buy eggs", Done: true, UserID: 1},
+		{Text: "buy bread", Done: false, UserID: 2},
+		{Text: "walk the dog", Done: false, UserID: 1},
+	})
+
+	r := &queryResolver{resolver}
+	done := false
+	userID := 1
+	filter := &model.TodoFilter{Done: &done, UserID: &userID, TextContains: strPtr("buy")}
+
+	ids := collectAllPages(t, r, 10, filter, nil)
+	if len(ids) != 1 {
+		t.Fatalf("expected exactly 1 matching todo, got %d: %v", len(ids), ids)
+	}
+
+	var todo models.Todo
+	if err := resolver.DB.First(&todo, ids[0]).Error; err != nil {
+		t.Fatalf("reload todo: %v", err)
+	}
+	if todo.Text != "buy milk" {
+		t.Fatalf("expected %q, got %q", "buy milk", todo.Text)
+	}
+}
+
+func strPtr(s string) *string { return &s }
@@ -5,11 +5,11 @@ type Todo struct {
 	Text   string
 	Done   bool
 	UserID int
-	User   User
 }
 
 type User struct {
 	ID    int
 	Name  string
+	Role  Role
 	Todos []Todo
 }
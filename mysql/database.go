@@ -0,0 +1,28 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/jamie/gqlgen-crud/models"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// Connect opens a connection to the MySQL server described by dsn, creates
+// the gqlgen database if it doesn't already exist, and runs auto-migrations
+// for the models this service owns.
+func Connect(dsn string) (*gorm.DB, error) {
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("connect to mysql: %w", err)
+	}
+
+	db.Exec("CREATE DATABASE IF NOT EXISTS gqlgen")
+	db.Exec("USE gqlgen")
+
+	if err := db.AutoMigrate(&models.Todo{}, &models.User{}); err != nil {
+		return nil, fmt.Errorf("automigrate: %w", err)
+	}
+
+	return db, nil
+}
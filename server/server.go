@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/gorilla/websocket"
+	"github.com/jamie/gqlgen-crud/auth"
+	"github.com/jamie/gqlgen-crud/graph"
+	"github.com/jamie/gqlgen-crud/graph/directives"
+	"github.com/jamie/gqlgen-crud/graph/generated"
+	"github.com/jamie/gqlgen-crud/graph/loaders"
+	"github.com/jamie/gqlgen-crud/graph/pubsub"
+	database "github.com/jamie/gqlgen-crud/mysql"
+)
+
+const defaultPort = "8080"
+
+func main() {
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = defaultPort
+	}
+
+	dsn := os.Getenv("DATABASE_DSN")
+	if dsn == "" {
+		dsn = "root:Foxconn123@(localhost)/"
+	}
+
+	authSecret := os.Getenv("AUTH_SECRET")
+	if authSecret == "" {
+		log.Fatal("AUTH_SECRET must be set")
+	}
+
+	db, err := database.Connect(dsn)
+	if err != nil {
+		log.Fatalf("connect to database: %v", err)
+	}
+
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{
+		Resolvers:  &graph.Resolver{DB: db, Broker: pubsub.NewBroker()},
+		Directives: generated.DirectiveRoot{HasRole: directives.HasRole},
+	}))
+
+	// The server uses the first transport that supports a request, so this
+	// Websocket transport is added before any other.
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		// A browser WebSocket client can't set an Authorization header on
+		// the handshake, so auth.Middleware finds no token for its
+		// subscriptions. InitFunc instead authenticates off the bearer token the client
+		// sends in the connection_init payload once the socket is open.
+		InitFunc: func(ctx context.Context, initPayload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+			tokenString := strings.TrimPrefix(initPayload.Authorization(), "Bearer ")
+			if tokenString == "" {
+				return ctx, &initPayload, nil
+			}
+
+			user, err := auth.ParseToken([]byte(authSecret), tokenString)
+			if err != nil {
+				return ctx, &initPayload, nil
+			}
+
+			return auth.WithUser(ctx, user), &initPayload, nil
+		},
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+	srv.Use(extension.Introspection{})
+
+	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
+	http.Handle("/query", auth.Middleware([]byte(authSecret), loaders.Middleware(db, srv)))
+
+	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
+	log.Fatal(http.ListenAndServe(":"+port, nil))
+}